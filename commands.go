@@ -0,0 +1,387 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+	"github.com/maloquacious/wsj/interpreter"
+	"github.com/maloquacious/wsj/session"
+)
+
+// commandContext carries everything a ReplCommand.Run needs to do its job.
+type commandContext struct {
+	rl     *readline.Instance
+	interp *interpreter.Interpreter
+	env    *replEnv
+}
+
+// ReplCommand is one "$name" REPL command, or one of its subcommands. Sub
+// holds nested subcommands (e.g. "on"/"off"/"status" under "debug"); Run may
+// be nil for a command that only exists to group subcommands, in which case
+// the registry reports "requires a subcommand" if it's invoked bare.
+type ReplCommand struct {
+	Name string
+	Help string
+	Sub  map[string]*ReplCommand
+	Run  func(ctx *commandContext, args []string) error
+}
+
+// replCommands is the registry of top-level "$" commands, keyed by name.
+// Building it as a registry (rather than a hard-coded switch) is what lets
+// $help and the readline completer be generated from the same metadata, and
+// lets other code register its own commands.
+var replCommands map[string]*ReplCommand
+
+// replCommands is filled in by init rather than by its declaration: $help's
+// Run reads replCommands (through sortedCommandNames), which would otherwise
+// be an initialization cycle.
+func init() {
+	replCommands = buildReplCommands()
+}
+
+func buildReplCommands() map[string]*ReplCommand {
+	cmds := make(map[string]*ReplCommand)
+	register := func(c *ReplCommand) { cmds[c.Name] = c }
+
+	register(&ReplCommand{
+		Name: "cwd",
+		Help: "print the current working directory",
+		Run: func(ctx *commandContext, args []string) error {
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			fmt.Println(wd)
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "debug",
+		Help: "debug [on|off|status] - toggle or show debug mode",
+		Sub: map[string]*ReplCommand{
+			"on": {
+				Name: "on",
+				Help: "enable debug mode",
+				Run: func(ctx *commandContext, args []string) error {
+					ctx.env.debug = true
+					ctx.interp.SetDebug(true)
+					fmt.Println("Debug mode now enabled")
+					return nil
+				},
+			},
+			"off": {
+				Name: "off",
+				Help: "disable debug mode",
+				Run: func(ctx *commandContext, args []string) error {
+					ctx.env.debug = false
+					ctx.interp.SetDebug(false)
+					fmt.Println("Debug mode now disabled")
+					return nil
+				},
+			},
+			"status": {
+				Name: "status",
+				Help: "show whether debug mode is enabled",
+				Run:  reportDebugStatus,
+			},
+		},
+		Run: reportDebugStatus,
+	})
+
+	register(&ReplCommand{
+		Name: "exit",
+		Help: "exit the REPL",
+		Run: func(ctx *commandContext, args []string) error {
+			os.Exit(0)
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "mode",
+		Help: "mode [NAME] - show or set the interpreter's runner mode",
+		Run: func(ctx *commandContext, args []string) error {
+			if len(args) == 0 {
+				fmt.Printf("current mode: %s (available: %s)\n", ctx.interp.Mode(), strings.Join(ctx.interp.Modes(), ", "))
+				return nil
+			}
+			if err := ctx.interp.SetMode(args[0]); err != nil {
+				return err
+			}
+			fmt.Printf("mode set to %s\n", args[0])
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "paste",
+		Help: "switch to raw multi-line paste capture, ending on Ctrl-D",
+		Run: func(ctx *commandContext, args []string) error {
+			input := capturePasteCommand(ctx.rl)
+			return runProgram(ctx.interp, input, ctx.env.debug)
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "hexes",
+		Help: "hexes list|show ID - inspect the current map's hexes",
+		Sub: map[string]*ReplCommand{
+			"list": {Name: "list", Help: "list hex ids", Run: notImplemented("$hexes list")},
+			"show": {Name: "show", Help: "show ID - show a single hex", Run: notImplemented("$hexes show")},
+		},
+		Run: notImplemented("$hexes"),
+	})
+
+	register(&ReplCommand{
+		Name: "vars",
+		Help: "vars list|get NAME|set NAME=VAL - inspect interpreter variables",
+		Sub: map[string]*ReplCommand{
+			"list": {
+				Name: "list",
+				Help: "list variable names",
+				Run: func(ctx *commandContext, args []string) error {
+					names := ctx.interp.VarNames()
+					if len(names) == 0 {
+						fmt.Println("(no variables set)")
+						return nil
+					}
+					for _, name := range names {
+						fmt.Println(name)
+					}
+					return nil
+				},
+			},
+			"get": {
+				Name: "get",
+				Help: "get NAME - print a variable's value",
+				Run: func(ctx *commandContext, args []string) error {
+					if len(args) == 0 {
+						return fmt.Errorf("usage: $vars get NAME")
+					}
+					value, ok := ctx.interp.Var(args[0])
+					if !ok {
+						return fmt.Errorf("no such variable: %s", args[0])
+					}
+					fmt.Println(value)
+					return nil
+				},
+			},
+			"set": {
+				Name: "set",
+				Help: "set NAME=VAL - assign a variable",
+				Run: func(ctx *commandContext, args []string) error {
+					if len(args) == 0 {
+						return fmt.Errorf("usage: $vars set NAME=VAL")
+					}
+					name, value, ok := strings.Cut(strings.Join(args, " "), "=")
+					if !ok {
+						return fmt.Errorf("usage: $vars set NAME=VAL")
+					}
+					name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+					ctx.interp.SetVar(name, value)
+					fmt.Printf("%s = %s\n", name, value)
+					return nil
+				},
+			},
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "load",
+		Help: "load FILE - restore a saved session",
+		Run: func(ctx *commandContext, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: $load FILE")
+			}
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("load: %w", err)
+			}
+			if err := ctx.interp.Restore(data); err != nil {
+				return err
+			}
+			ctx.env.debug = ctx.interp.Debug()
+			fmt.Printf("session restored from %s\n", args[0])
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "save",
+		Help: "save FILE - save the current session",
+		Run: func(ctx *commandContext, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: $save FILE")
+			}
+			data, err := ctx.interp.Snapshot()
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(args[0], data, 0o644); err != nil {
+				return fmt.Errorf("save: %w", err)
+			}
+			fmt.Printf("session saved to %s\n", args[0])
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "transcript",
+		Help: "transcript on FILE|off - tee prompts, input, and output to FILE",
+		Sub: map[string]*ReplCommand{
+			"on": {
+				Name: "on",
+				Help: "on FILE - start teeing the session to FILE",
+				Run: func(ctx *commandContext, args []string) error {
+					if len(args) == 0 {
+						return fmt.Errorf("usage: $transcript on FILE")
+					}
+					t, err := session.OpenTranscript(args[0])
+					if err != nil {
+						return err
+					}
+					_ = ctx.env.transcript.Close()
+					ctx.env.transcript = t
+					fmt.Printf("transcript recording to %s\n", args[0])
+					return nil
+				},
+			},
+			"off": {
+				Name: "off",
+				Help: "stop teeing the session",
+				Run: func(ctx *commandContext, args []string) error {
+					if err := ctx.env.transcript.Close(); err != nil {
+						return err
+					}
+					ctx.env.transcript = nil
+					fmt.Println("transcript stopped")
+					return nil
+				},
+			},
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "time",
+		Help: "time EXPR - run EXPR and report how long it took",
+		Run: func(ctx *commandContext, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("usage: $time EXPR")
+			}
+			start := time.Now()
+			err := runProgram(ctx.interp, strings.Join(args, " "), ctx.env.debug)
+			fmt.Printf("(%s)\n", time.Since(start))
+			return err
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "version",
+		Help: "print the REPL version",
+		Run: func(ctx *commandContext, args []string) error {
+			fmt.Printf("repl %s\n", version.String())
+			return nil
+		},
+	})
+
+	register(&ReplCommand{
+		Name: "help",
+		Help: "help [COMMAND] - list commands, or show help for one",
+		Run: func(ctx *commandContext, args []string) error {
+			if len(args) == 0 {
+				for _, name := range sortedCommandNames() {
+					fmt.Printf("$%-10s %s\n", name, cmds[name].Help)
+				}
+				return nil
+			}
+			cmd, ok := cmds[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown command: %s", args[0])
+			}
+			fmt.Printf("$%s: %s\n", cmd.Name, cmd.Help)
+			for _, sub := range sortedSubcommands(cmd) {
+				fmt.Printf("  %s: %s\n", sub.Name, sub.Help)
+			}
+			return nil
+		},
+	})
+
+	return cmds
+}
+
+func reportDebugStatus(ctx *commandContext, args []string) error {
+	if ctx.env.debug {
+		fmt.Println("Debug mode is enabled")
+	} else {
+		fmt.Println("Debug mode is disabled")
+	}
+	return nil
+}
+
+func notImplemented(name string) func(ctx *commandContext, args []string) error {
+	return func(ctx *commandContext, args []string) error {
+		fmt.Printf("%s is not implemented yet\n", name)
+		return nil
+	}
+}
+
+func sortedCommandNames() []string {
+	names := make([]string, 0, len(replCommands))
+	for name := range replCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedSubcommands(cmd *ReplCommand) []*ReplCommand {
+	names := make([]string, 0, len(cmd.Sub))
+	for name := range cmd.Sub {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	subs := make([]*ReplCommand, len(names))
+	for i, name := range names {
+		subs[i] = cmd.Sub[name]
+	}
+	return subs
+}
+
+// dispatchReplCommand parses a "$name [sub] [args...]" line and runs the
+// matching command or subcommand, falling back to its parent's Run (e.g.
+// "$debug" with no subcommand reports status) when no subcommand matches.
+func dispatchReplCommand(ctx *commandContext, line string) {
+	line = strings.TrimPrefix(strings.TrimSpace(line), "$")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return
+	}
+
+	cmd, ok := replCommands[fields[0]]
+	if !ok {
+		fmt.Printf("Unknown REPL command: %s\n", fields[0])
+		return
+	}
+	args := fields[1:]
+
+	if len(args) > 0 {
+		if sub, ok := cmd.Sub[args[0]]; ok {
+			cmd, args = sub, args[1:]
+		}
+	}
+
+	if cmd.Run == nil {
+		fmt.Printf("$%s requires a subcommand\n", cmd.Name)
+		return
+	}
+	if err := cmd.Run(ctx, args); err != nil {
+		fmt.Println(err)
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import "strings"
+
+// replCompleter implements readline.AutoCompleter against the replCommands
+// registry, so "$" + Tab completes command names and "$cmd " + Tab
+// completes that command's subcommand names.
+type replCompleter struct{}
+
+func (replCompleter) Do(line []rune, pos int) (newLine [][]rune, length int) {
+	typed := string(line[:pos])
+	if !strings.HasPrefix(typed, "$") {
+		return nil, 0
+	}
+	fields := strings.Fields(strings.TrimPrefix(typed, "$"))
+	completingNewWord := strings.HasSuffix(typed, " ")
+
+	// Still typing the command name itself.
+	if len(fields) == 0 || (len(fields) == 1 && !completingNewWord) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		return matching(sortedCommandNames(), prefix), len(prefix)
+	}
+
+	cmd, ok := replCommands[fields[0]]
+	if !ok || len(cmd.Sub) == 0 {
+		return nil, 0
+	}
+
+	// Typing (or about to type) the subcommand name.
+	if len(fields) == 1 || (len(fields) == 2 && !completingNewWord) {
+		prefix := ""
+		if len(fields) == 2 {
+			prefix = fields[1]
+		}
+		names := make([]string, 0, len(cmd.Sub))
+		for name := range cmd.Sub {
+			names = append(names, name)
+		}
+		return matching(names, prefix), len(prefix)
+	}
+
+	return nil, 0
+}
+
+// matching returns the remainder (past prefix) of every name in names that
+// starts with prefix, as the rune slices readline.AutoCompleter expects.
+func matching(names []string, prefix string) [][]rune {
+	var out [][]rune
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			out = append(out, []rune(name[len(prefix):]))
+		}
+	}
+	return out
+}
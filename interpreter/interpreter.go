@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package interpreter executes parsed WSJ programs.
+package interpreter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Result is whatever a runner mode produces from a chunk of source. It is
+// intentionally small for now; modes that need richer data can stash it in
+// Output and let callers type-assert or format it themselves.
+type Result struct {
+	Output string
+}
+
+// RunnerFunc executes a chunk of source and returns the result.
+type RunnerFunc func(src string) (Result, error)
+
+// Event is passed to hooks registered with OnEvent.
+type Event struct {
+	Hook   string
+	Mode   string
+	Source string
+	Result Result
+	Err    error
+}
+
+// HookFunc observes a lifecycle event. Hooks must not block for long; they
+// run synchronously on the calling goroutine.
+type HookFunc func(Event)
+
+const (
+	HookBeforeRun = "before-run"
+	HookAfterRun  = "after-run"
+	HookRunError  = "run-error"
+)
+
+// Interpreter holds the state needed to execute WSJ programs: the current
+// runner mode, the registry of available modes, and any lifecycle hooks
+// scripts or the REPL have subscribed to.
+type Interpreter struct {
+	debug bool
+	vars  map[string]string
+	funcs map[string]string
+
+	mode    string
+	runners map[string]RunnerFunc
+	hooks   map[string][]HookFunc
+}
+
+// New returns an Interpreter with the built-in runner modes ("wsj",
+// "parse-only", and "shell") registered and "wsj" selected as the default.
+func New() (*Interpreter, error) {
+	interp := &Interpreter{
+		mode:    "wsj",
+		vars:    make(map[string]string),
+		funcs:   make(map[string]string),
+		runners: make(map[string]RunnerFunc),
+		hooks:   make(map[string][]HookFunc),
+	}
+	interp.RegisterRunner("wsj", interp.runWSJ)
+	interp.RegisterRunner("parse-only", interp.runParseOnly)
+	interp.RegisterRunner("shell", interp.runShell)
+	return interp, nil
+}
+
+// Debug reports whether debug mode is enabled.
+func (i *Interpreter) Debug() bool {
+	return i.debug
+}
+
+// SetDebug enables or disables debug mode.
+func (i *Interpreter) SetDebug(debug bool) {
+	i.debug = debug
+}
+
+// RegisterRunner adds (or replaces) a named runner mode. Scripts and hosts
+// embedding the interpreter can call this to add DSLs, dry-run linters, or
+// anything else that turns a line of input into a Result.
+func (i *Interpreter) RegisterRunner(name string, fn RunnerFunc) {
+	i.runners[name] = fn
+}
+
+// Modes returns the names of the registered runner modes, sorted.
+func (i *Interpreter) Modes() []string {
+	names := make([]string, 0, len(i.runners))
+	for name := range i.runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mode returns the name of the currently selected runner mode.
+func (i *Interpreter) Mode() string {
+	return i.mode
+}
+
+// wsjParsingModes are the built-in runner modes whose RunnerFunc parses its
+// source as WSJ. Callers that gate WSJ-specific behavior -- the REPL's
+// parser.Continue prompt-continuation check, a pre-parse for a debug dump --
+// should only do so when ParsesWSJ is true; "shell" and any custom mode a
+// host registers are free-form and must not be force-fed through the WSJ
+// parser.
+var wsjParsingModes = map[string]bool{
+	"wsj":        true,
+	"parse-only": true,
+}
+
+// ParsesWSJ reports whether the current runner mode expects its source to be
+// a WSJ program. It's false for "shell" (and any custom mode a host
+// registers), which take arbitrary source the WSJ parser would reject.
+func (i *Interpreter) ParsesWSJ() bool {
+	return wsjParsingModes[i.mode]
+}
+
+// SetMode selects the runner mode used by Run. It returns an error if name
+// hasn't been registered with RegisterRunner.
+func (i *Interpreter) SetMode(name string) error {
+	if _, ok := i.runners[name]; !ok {
+		return fmt.Errorf("unknown runner mode %q", name)
+	}
+	i.mode = name
+	return nil
+}
+
+// OnEvent subscribes fn to the named lifecycle hook (HookBeforeRun,
+// HookAfterRun, or HookRunError). Multiple hooks may be registered for the
+// same event; they fire in registration order.
+func (i *Interpreter) OnEvent(hook string, fn HookFunc) {
+	i.hooks[hook] = append(i.hooks[hook], fn)
+}
+
+func (i *Interpreter) fire(hook string, ev Event) {
+	for _, fn := range i.hooks[hook] {
+		fn(ev)
+	}
+}
+
+// Run executes src with the current runner mode, firing before-run,
+// after-run, and run-error hooks around the call.
+func (i *Interpreter) Run(src string) (Result, error) {
+	fn, ok := i.runners[i.mode]
+	if !ok {
+		return Result{}, fmt.Errorf("no runner registered for mode %q", i.mode)
+	}
+
+	i.fire(HookBeforeRun, Event{Hook: HookBeforeRun, Mode: i.mode, Source: src})
+
+	result, err := fn(src)
+	if err != nil {
+		i.fire(HookRunError, Event{Hook: HookRunError, Mode: i.mode, Source: src, Err: err})
+		return result, err
+	}
+
+	i.fire(HookAfterRun, Event{Hook: HookAfterRun, Mode: i.mode, Source: src, Result: result})
+	return result, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package interpreter
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/maloquacious/wsj/ast"
+	"github.com/maloquacious/wsj/parser"
+)
+
+// runWSJ parses src as a WSJ program. It does not execute it yet: there is
+// no statement walker in the interpreter, so this is parsing only, same as
+// runParseOnly. The output says so plainly rather than claiming statements
+// ran.
+//
+// TODO: walk prog.Statements and execute them once the interpreter has an
+// evaluator; until then "wsj" mode is parse-only in all but name.
+func (i *Interpreter) runWSJ(src string) (Result, error) {
+	result, err := parser.Parse("", []byte(src))
+	if err != nil {
+		return Result{}, fmt.Errorf("parse error: %w", err)
+	}
+
+	prog, ok := result.(*ast.Program)
+	if !ok {
+		return Result{}, fmt.Errorf("unexpected AST type: %T", result)
+	}
+
+	return Result{Output: fmt.Sprintf("parsed %d statement(s) (execution not yet implemented)", len(prog.Statements))}, nil
+}
+
+// runParseOnly parses src and reports success without executing anything.
+// This is the same "just parse it" behavior runProgram has always had in
+// debug mode, exposed as its own mode so it can be selected without -debug.
+func (i *Interpreter) runParseOnly(src string) (Result, error) {
+	result, err := parser.Parse("", []byte(src))
+	if err != nil {
+		return Result{}, fmt.Errorf("parse error: %w", err)
+	}
+
+	prog, ok := result.(*ast.Program)
+	if !ok {
+		return Result{}, fmt.Errorf("unexpected AST type: %T", result)
+	}
+
+	return Result{Output: fmt.Sprintf("parsed %d statement(s)", len(prog.Statements))}, nil
+}
+
+// runShell shells out to $SHELL -c src, falling back to /bin/sh if $SHELL
+// isn't set, and returns its combined output.
+func (i *Interpreter) runShell(src string) (Result, error) {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	cmd := exec.Command(shell, "-c", src)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.CombinedOutput()
+	output := strings.TrimRight(string(out), "\n")
+	if err != nil {
+		return Result{Output: output}, fmt.Errorf("shell: %w", err)
+	}
+	return Result{Output: output}, nil
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package interpreter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// snapshotVersion is bumped whenever the shape of snapshotV1 changes in a
+// way that isn't backward compatible, so Restore can reject a blob it
+// doesn't know how to read instead of silently misinterpreting it.
+const snapshotVersion = 1
+
+// snapshotV1 is the on-disk shape of a saved session: the top-level
+// environment an Interpreter needs to pick back up where it left off.
+type snapshotV1 struct {
+	Version int               `json:"version"`
+	Mode    string            `json:"mode"`
+	Debug   bool              `json:"debug"`
+	Vars    map[string]string `json:"vars"`
+	Funcs   map[string]string `json:"funcs"`
+}
+
+// Snapshot captures the top-level environment -- variables, user-defined
+// functions, the current runner mode, and the debug flag -- as a versioned
+// JSON blob suitable for writing to disk with $save.
+func (i *Interpreter) Snapshot() ([]byte, error) {
+	snap := snapshotV1{
+		Version: snapshotVersion,
+		Mode:    i.mode,
+		Debug:   i.debug,
+		Vars:    i.vars,
+		Funcs:   i.funcs,
+	}
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	return data, nil
+}
+
+// Restore replaces the interpreter's top-level environment with the one
+// captured in data, as produced by Snapshot. It returns an error if data
+// isn't a snapshot this version of the interpreter understands, or if it
+// names a runner mode that isn't registered.
+func (i *Interpreter) Restore(data []byte) error {
+	var snap snapshotV1
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("restore: unsupported snapshot version %d", snap.Version)
+	}
+	if err := i.SetMode(snap.Mode); err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	i.debug = snap.Debug
+	i.vars = snap.Vars
+	i.funcs = snap.Funcs
+	if i.vars == nil {
+		i.vars = make(map[string]string)
+	}
+	if i.funcs == nil {
+		i.funcs = make(map[string]string)
+	}
+	return nil
+}
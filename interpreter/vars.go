@@ -0,0 +1,28 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package interpreter
+
+import "sort"
+
+// VarNames returns the names of the top-level variables currently set,
+// sorted.
+func (i *Interpreter) VarNames() []string {
+	names := make([]string, 0, len(i.vars))
+	for name := range i.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Var returns the value of the named top-level variable and whether it was
+// set.
+func (i *Interpreter) Var(name string) (string, bool) {
+	value, ok := i.vars[name]
+	return value, ok
+}
+
+// SetVar assigns a top-level variable.
+func (i *Interpreter) SetVar(name, value string) {
+	i.vars[name] = value
+}
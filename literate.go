@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/maloquacious/wsj/interpreter"
+)
+
+// codeBlock is a single fenced ```wsj code block pulled out of a Markdown
+// file, in document order.
+type codeBlock struct {
+	Name      string // from a {name=...} attribute on the opening fence, or "" if absent
+	Index     int    // 1-based position among all wsj blocks in the document
+	StartLine int    // 1-based Markdown line number of the first line of code
+	Lines     []string
+}
+
+var fenceNameAttr = regexp.MustCompile(`name\s*=\s*"?([^",}\s]+)"?`)
+
+// extractWSJBlocks scans Markdown source for fenced code blocks tagged
+// ```wsj (optionally followed by a `{name=...}` attribute) and returns them
+// in document order.
+func extractWSJBlocks(markdown []byte) []codeBlock {
+	var blocks []codeBlock
+	var current *codeBlock
+
+	lines := strings.Split(string(markdown), "\n")
+	for lineNo, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if current == nil {
+			if !strings.HasPrefix(trimmed, "```wsj") {
+				continue
+			}
+			name := ""
+			if m := fenceNameAttr.FindStringSubmatch(trimmed); m != nil {
+				name = m[1]
+			}
+			current = &codeBlock{
+				Name:      name,
+				Index:     len(blocks) + 1,
+				StartLine: lineNo + 2, // code starts on the line after the fence
+			}
+			continue
+		}
+
+		if trimmed == "```" {
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+
+		current.Lines = append(current.Lines, line)
+	}
+
+	return blocks
+}
+
+// runMarkdownFile runs the ```wsj code blocks embedded in a Markdown file.
+// By default every block is concatenated in document order and run as a
+// single program; blockName restricts execution to the single block with
+// that name; listBlocks, if set, just prints the block names and returns.
+func runMarkdownFile(filename string, debug bool, blockName string, listBlocks bool) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", filename, err)
+	}
+
+	blocks := extractWSJBlocks(data)
+
+	if listBlocks {
+		for _, b := range blocks {
+			name := b.Name
+			if name == "" {
+				name = fmt.Sprintf("(unnamed #%d)", b.Index)
+			}
+			fmt.Printf("%s\tline %d\n", name, b.StartLine)
+		}
+		return nil
+	}
+
+	if blockName != "" {
+		found := false
+		for _, b := range blocks {
+			if b.Name == blockName {
+				blocks, found = []codeBlock{b}, true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no block named %q in %q", blockName, filename)
+		}
+	}
+
+	var program strings.Builder
+	var lineMap []int // lineMap[i] = original Markdown line number of program line i+1
+	for _, b := range blocks {
+		for i, line := range b.Lines {
+			program.WriteString(line)
+			program.WriteString("\n")
+			lineMap = append(lineMap, b.StartLine+i)
+		}
+	}
+
+	interp, err := interpreter.New()
+	if err != nil {
+		return err
+	}
+
+	if err := runProgram(interp, program.String(), debug); err != nil {
+		return fmt.Errorf("%s: %w", filename, remapLine(err, lineMap))
+	}
+	return nil
+}
+
+// parserPosition matches the "line:col (offset)" position the generated
+// parser embeds in its error messages (e.g. ":3:5 (12): no match found").
+var parserPosition = regexp.MustCompile(`(\d+):(\d+) \(\d+\)`)
+
+// remapLine rewrites the line number in a "line:col (offset)" parser
+// position in err's message -- a line number in the concatenated program --
+// to the corresponding line number in the original Markdown file. If err
+// doesn't contain such a position it is returned unchanged.
+func remapLine(err error, lineMap []int) error {
+	msg := err.Error()
+	match := parserPosition.FindStringSubmatchIndex(msg)
+	if match == nil {
+		return err
+	}
+
+	programLine, convErr := strconv.Atoi(msg[match[2]:match[3]])
+	if convErr != nil || programLine < 1 || programLine > len(lineMap) {
+		return err
+	}
+
+	mapped := msg[:match[2]] + strconv.Itoa(lineMap[programLine-1]) + msg[match[3]:]
+	return fmt.Errorf("%s", mapped)
+}
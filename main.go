@@ -23,9 +23,11 @@ var (
 
 func main() {
 	var (
-		showVersion   = flag.Bool("version", false, "show version and exit")
-		showBuildInfo = flag.Bool("build-info", false, "show build information and exit")
-		debugFlag     = flag.Bool("debug", false, "enable debug mode")
+		showVersion    = flag.Bool("version", false, "show version and exit")
+		showBuildInfo  = flag.Bool("build-info", false, "show build information and exit")
+		debugFlag      = flag.Bool("debug", false, "enable debug mode")
+		blockFlag      = flag.String("block", "", "run only the named code block (literate .md scripts)")
+		listBlocksFlag = flag.Bool("list-blocks", false, "list code block names in a literate .md script and exit")
 	)
 
 	flag.Parse()
@@ -59,6 +61,13 @@ func main() {
 			os.Exit(1)
 		}
 
+	case len(args) == 1 && strings.HasSuffix(args[0], ".md"):
+		// Run the fenced ```wsj code blocks in a literate Markdown script
+		if err := runMarkdownFile(args[0], *debugFlag, *blockFlag, *listBlocksFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
 	default:
 		// Evaluate the arguments as program
 		program := strings.Join(args, " ")
@@ -84,18 +93,52 @@ func runScriptFile(filename string, debug bool) error {
 	return runProgram(nil, input, debug)
 }
 
+// runProgram runs input under interp's current runner mode. interp may be
+// nil (e.g. a one-off eval from the command line with no session to attach
+// to), in which case it falls back to the historical "just parse it"
+// behavior -- there's no runner to hand the source to.
+//
+// The pre-parse here (and the debug dump it gates) only applies when interp
+// is nil or its mode parses WSJ (interp.ParsesWSJ()): a "shell" mode's
+// source is never WSJ and must not be forced through parser.Parse. For
+// modes that do parse WSJ, interp.Run's own runner (runWSJ/runParseOnly)
+// parses again; we only pay that cost when debug is on and there's a dump
+// to show, rather than on every call.
 func runProgram(interp *interpreter.Interpreter, input string, debug bool) error {
-	result, err := parser.Parse("", []byte(input))
-	if err != nil {
-		return fmt.Errorf("parse error: %w", err)
-	}
+	if interp == nil {
+		result, err := parser.Parse("", []byte(input))
+		if err != nil {
+			return fmt.Errorf("parse error: %w", err)
+		}
 
-	prog, ok := result.(*ast.Program)
-	if !ok {
-		return fmt.Errorf("unexpected AST type: %T", result)
+		prog, ok := result.(*ast.Program)
+		if !ok {
+			return fmt.Errorf("unexpected AST type: %T", result)
+		}
+
+		if debug {
+			spew.Dump(prog)
+			fmt.Println("Parse successful!")
+			for i, stmt := range prog.Statements {
+				fmt.Printf("Statement %d: %#v\n", i+1, stmt)
+			}
+		}
+
+		fmt.Println("Program parsed successfully")
+		return nil
 	}
 
-	if debug {
+	if debug && interp.ParsesWSJ() {
+		result, err := parser.Parse("", []byte(input))
+		if err != nil {
+			return fmt.Errorf("parse error: %w", err)
+		}
+
+		prog, ok := result.(*ast.Program)
+		if !ok {
+			return fmt.Errorf("unexpected AST type: %T", result)
+		}
+
 		spew.Dump(prog)
 		fmt.Println("Parse successful!")
 		for i, stmt := range prog.Statements {
@@ -103,9 +146,12 @@ func runProgram(interp *interpreter.Interpreter, input string, debug bool) error
 		}
 	}
 
-	// TODO: Execute the program using the interpreter
-	// For now, just indicate successful parsing
-	fmt.Println("Program parsed successfully")
-
+	res, err := interp.Run(input)
+	if err != nil {
+		return err
+	}
+	if res.Output != "" {
+		fmt.Println(res.Output)
+	}
 	return nil
 }
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package parser
+
+import "fmt"
+
+// IncompleteError indicates that the input parsed so far is a valid prefix of
+// a larger WSJ program: some string, bracket, or block keyword was opened but
+// never closed, or the last token expects more to follow it (e.g. a trailing
+// binary operator). Callers (typically a REPL) should read another line and
+// retry rather than treating this as a hard syntax error.
+type IncompleteError struct {
+	// Unclosed names the construct that is still open, e.g. "if", "(",
+	// "string literal" for an unterminated string (of either quote kind), or
+	// "expression" when the input ends mid-expression.
+	Unclosed string
+}
+
+func (e *IncompleteError) Error() string {
+	return fmt.Sprintf("incomplete input: unclosed %s", e.Unclosed)
+}
+
+// blockKeywords are the keywords that open a block that must be closed by a
+// matching "end".
+var blockKeywords = map[string]bool{
+	"if":  true,
+	"for": true,
+}
+
+// trailingOperators are tokens that must be followed by an operand: input
+// that ends with one of these (outside a string/comment) is a valid prefix
+// of a larger expression, not a finished statement.
+var trailingOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true,
+	"=": true, "==": true, "!=": true,
+	"<": true, ">": true, "<=": true, ">=": true,
+	"&&": true, "||": true, ",": true,
+}
+
+// Continue reports whether src is an incomplete prefix of a WSJ program. It
+// tracks string/comment state, bracket and block-keyword depth, and the
+// last token seen, without building an AST, so it can be called cheaply
+// after every line a REPL reads.
+//
+// It returns a non-nil *IncompleteError when more input is expected, or nil
+// when src looks complete enough to hand to Parse. Continue never reports a
+// hard syntax error itself -- that's still Parse's job.
+func Continue(src string) *IncompleteError {
+	runes := []rune(src)
+
+	var (
+		inString    bool
+		stringQuote rune
+		parens      int
+		brackets    int
+		braces      int
+		blockStack  []string
+		word        []rune
+		op          []rune
+		lastToken   string
+	)
+
+	flushWord := func() {
+		if len(word) == 0 {
+			return
+		}
+		w := string(word)
+		word = word[:0]
+		lastToken = w
+		switch {
+		case blockKeywords[w]:
+			blockStack = append(blockStack, w)
+		case w == "end":
+			if len(blockStack) > 0 {
+				blockStack = blockStack[:len(blockStack)-1]
+			}
+		}
+	}
+
+	flushOp := func() {
+		if len(op) == 0 {
+			return
+		}
+		lastToken = string(op)
+		op = op[:0]
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if inString {
+			switch r {
+			case '\\':
+				// skip the escaped character, if any
+				i++
+			case stringQuote:
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case r == '"' || r == '\'':
+			flushWord()
+			flushOp()
+			inString = true
+			stringQuote = r
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			flushWord()
+			flushOp()
+			// rest of the line is a comment
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case r == '(':
+			flushWord()
+			flushOp()
+			parens++
+			lastToken = ""
+		case r == ')':
+			flushWord()
+			flushOp()
+			if parens > 0 {
+				parens--
+			}
+			lastToken = ")"
+		case r == '[':
+			flushWord()
+			flushOp()
+			brackets++
+			lastToken = ""
+		case r == ']':
+			flushWord()
+			flushOp()
+			if brackets > 0 {
+				brackets--
+			}
+			lastToken = "]"
+		case r == '{':
+			flushWord()
+			flushOp()
+			braces++
+			lastToken = ""
+		case r == '}':
+			flushWord()
+			flushOp()
+			if braces > 0 {
+				braces--
+			}
+			lastToken = "}"
+		case isWordRune(r):
+			flushOp()
+			word = append(word, r)
+		case isOperatorRune(r):
+			flushWord()
+			op = append(op, r)
+		default:
+			flushWord()
+			flushOp()
+		}
+	}
+	flushWord()
+	flushOp()
+
+	switch {
+	case inString:
+		return &IncompleteError{Unclosed: "string literal"}
+	case parens > 0:
+		return &IncompleteError{Unclosed: "("}
+	case brackets > 0:
+		return &IncompleteError{Unclosed: "["}
+	case braces > 0:
+		return &IncompleteError{Unclosed: "{"}
+	case len(blockStack) > 0:
+		return &IncompleteError{Unclosed: blockStack[len(blockStack)-1]}
+	case trailingOperators[lastToken]:
+		return &IncompleteError{Unclosed: "expression"}
+	}
+	return nil
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' ||
+		(r >= 'a' && r <= 'z') ||
+		(r >= 'A' && r <= 'Z') ||
+		(r >= '0' && r <= '9')
+}
+
+func isOperatorRune(r rune) bool {
+	switch r {
+	case '+', '-', '*', '/', '%', '=', '!', '<', '>', '&', '|', ',':
+		return true
+	}
+	return false
+}
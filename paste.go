@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// Bracketed-paste sentinels emitted by xterm-compatible terminals when
+// bracketed paste mode is enabled: everything between them arrived in one
+// paste, not one line at a time from the user.
+const (
+	bracketedPasteStart = "\x1b[200~"
+	bracketedPasteEnd   = "\x1b[201~"
+)
+
+// capturePaste reads from rl until it sees bracketedPasteEnd or rl.Readline
+// returns an error (EOF, an interrupt mid-paste, etc.), stripping both
+// sentinels, and returns the captured text as a single block. first is the
+// line that triggered the capture, already known to contain
+// bracketedPasteStart.
+func capturePaste(rl *readline.Instance, first string) string {
+	var b strings.Builder
+
+	text := strings.Replace(first, bracketedPasteStart, "", 1)
+	if end := strings.Index(text, bracketedPasteEnd); end >= 0 {
+		b.WriteString(text[:end])
+		return b.String()
+	}
+	b.WriteString(text)
+
+	for {
+		line, err := rl.Readline()
+		if end := strings.Index(line, bracketedPasteEnd); end >= 0 {
+			b.WriteString("\n")
+			b.WriteString(line[:end])
+			return b.String()
+		}
+		if err != nil {
+			return b.String()
+		}
+		b.WriteString("\n")
+		b.WriteString(line)
+	}
+}
+
+// capturePasteCommand implements the $paste REPL command: it switches
+// readline into a raw multi-line capture mode that reads until EOF
+// (Ctrl-D), bypassing parser.Continue entirely, and returns the whole
+// buffer to be fed to runProgram as one unit.
+func capturePasteCommand(rl *readline.Instance) string {
+	rl.SetPrompt("paste (Ctrl-D to finish)> ")
+	defer rl.SetPrompt("> ")
+
+	var lines []string
+	for {
+		line, err := rl.Readline()
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
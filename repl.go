@@ -6,15 +6,17 @@ import (
 	"fmt"
 	"io"
 	"log"
-	"os"
 	"strings"
 
 	"github.com/chzyer/readline"
 	"github.com/maloquacious/wsj/interpreter"
+	"github.com/maloquacious/wsj/parser"
+	"github.com/maloquacious/wsj/session"
 )
 
 type replEnv struct {
-	debug bool
+	debug      bool
+	transcript *session.Transcript
 }
 
 func runREPL(debug bool) error {
@@ -22,12 +24,18 @@ func runREPL(debug bool) error {
 
 	renv := &replEnv{debug: debug}
 
+	historyFile, err := session.HistoryPath()
+	if err != nil {
+		historyFile = "" // readline treats an empty HistoryFile as "don't persist history"
+	}
+
 	rl, err := readline.NewEx(&readline.Config{
 		Prompt:            "> ",
-		HistoryFile:       "/tmp/wsh.repl.history", // todo: replace with ~/.wsh.history
+		HistoryFile:       historyFile,
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
 		HistorySearchFold: true,
+		AutoComplete:      replCompleter{},
 	})
 	if err != nil {
 		panic(fmt.Sprintf("failed to initialize readline: %v\n", err))
@@ -40,11 +48,14 @@ func runREPL(debug bool) error {
 	if err != nil {
 		return err
 	}
+	ctx := &commandContext{rl: rl, interp: interp, env: renv}
+	defer func() { _ = renv.transcript.Close() }()
 
 	println("WSJ REPL - type `$exit` to quit, `$help` for help\n")
 
 	var lines []string
 	for {
+		renv.transcript.Log("prompt", rl.Config.Prompt)
 		line, err := rl.Readline()
 		if err == readline.ErrInterrupt {
 			if len(lines) > 0 {
@@ -55,90 +66,36 @@ func runREPL(debug bool) error {
 		} else if err == io.EOF {
 			break
 		}
+		renv.transcript.Log("input", line)
+
+		if strings.Contains(line, bracketedPasteStart) {
+			input := capturePaste(rl, line)
+			renv.runLogged(func() { runProgram(interp, input, renv.debug) })
+			continue
+		}
 
 		if strings.TrimSpace(line) == "" {
 			continue
 		} else if strings.HasPrefix(strings.TrimSpace(line), "$") {
-			handleReplCommand(interp, renv, strings.TrimSpace(line))
+			renv.runLogged(func() { dispatchReplCommand(ctx, strings.TrimSpace(line)) })
 			continue
 		}
 
 		lines = append(lines, line)
-		if blockComplete(lines) {
-			input := strings.Join(lines, "\n")
-			lines = nil
+		input := strings.Join(lines, "\n")
+		if interp.ParsesWSJ() {
+			if incomplete := parser.Continue(input); incomplete != nil {
+				rl.SetPrompt(". ")
+				continue
+			}
+		}
+		lines = nil
 
-			// Change prompt back to single line
-			rl.SetPrompt("> ")
+		// Change prompt back to single line
+		rl.SetPrompt("> ")
 
-			runProgram(interp, input, renv.debug)
-		} else {
-			rl.SetPrompt(". ")
-		}
+		renv.runLogged(func() { runProgram(interp, input, renv.debug) })
 	}
 	fmt.Printf("\n\n")
 	return nil
 }
-
-// A simple heuristic to know when the user is done typing a block:
-// 📌 Note: This is crude, but good enough for early usage. Eventually you can:
-// * Track open control blocks more reliably
-// * Use the parser to detect incomplete inputs (e.g., recoverable errors)
-func blockComplete(lines []string) bool {
-	text := strings.Join(lines, "\n")
-	open := strings.Count(text, "if") + strings.Count(text, "for")
-	close := strings.Count(text, "end")
-	return close >= open
-}
-
-func handleReplCommand(interp *interpreter.Interpreter, env *replEnv, line string) {
-	// drop any leading spaces and the '$' that signifies repl commands
-	line = strings.TrimPrefix(strings.TrimSpace(line), "$")
-	args := strings.Fields(line)
-	if len(args) == 0 {
-		return
-	}
-	switch args[0] {
-	case "cwd":
-		wd, err := os.Getwd()
-		if err != nil {
-			println(err)
-			return
-		}
-		println(wd)
-		return
-	case "debug":
-		if len(args) > 1 && args[1] == "on" {
-			env.debug = true
-			fmt.Println("Debug mode now enabled")
-		} else if len(args) > 1 && args[1] == "off" {
-			env.debug = false
-			fmt.Println("Debug mode now disabled")
-		} else if env.debug {
-			fmt.Println("Debug mode is enabled")
-		} else {
-			fmt.Println("Debug mode is disabled")
-		}
-		return
-	case "exit":
-		os.Exit(0)
-	case "hexes":
-		fmt.Printf("$hexes is not implemented yet\n")
-		//	for i, h := range vm.Root().Hexes {
-		//		fmt.Printf("hexes[%d] = %s\n", i, h.Terrain)
-		//	}
-		return
-	case "vars":
-		fmt.Printf("$vars is not implemented yet\n")
-		//	for k := range vm.Vars() {
-		//		fmt.Println(k)
-		//	}
-		return
-	case "version":
-		println(fmt.Sprintf("repl %s", version.String()))
-		return
-
-	default:
-		fmt.Printf("Unknown REPL command: %s\n", args[0])
-	}
-}
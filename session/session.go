@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package session manages REPL-session concerns that live outside any one
+// interpreter instance: where command history is stored, and how a
+// transcript of a session is recorded for reproducible bug reports.
+package session
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// HistoryPath returns the path readline should use for command history,
+// creating its parent directory if needed. It prefers
+// $XDG_STATE_HOME/wsj/history and falls back to ~/.wsj_history.
+func HistoryPath() (string, error) {
+	if xdg := os.Getenv("XDG_STATE_HOME"); xdg != "" {
+		dir := filepath.Join(xdg, "wsj")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", fmt.Errorf("history path: %w", err)
+		}
+		return filepath.Join(dir, "history"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("history path: %w", err)
+	}
+	return filepath.Join(home, ".wsj_history"), nil
+}
+
+// Transcript tees REPL prompts, input, and output to a file, so a session
+// can be replayed later for a reproducible bug report.
+type Transcript struct {
+	f *os.File
+}
+
+// OpenTranscript opens (creating or appending to) the transcript file at
+// path.
+func OpenTranscript(path string) (*Transcript, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("transcript: %w", err)
+	}
+	return &Transcript{f: f}, nil
+}
+
+// Log appends one timestamped line of the given kind ("prompt", "input", or
+// "output") to the transcript. It is a no-op on a nil Transcript or an
+// empty line, so call sites don't need to guard every call.
+func (t *Transcript) Log(kind, text string) {
+	if t == nil || t.f == nil || text == "" {
+		return
+	}
+	fmt.Fprintf(t.f, "%s [%s] %s\n", time.Now().Format(time.RFC3339), kind, text)
+}
+
+// Close closes the transcript file. It is safe to call on a nil Transcript.
+func (t *Transcript) Close() error {
+	if t == nil || t.f == nil {
+		return nil
+	}
+	return t.f.Close()
+}
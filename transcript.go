@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// runLogged runs fn, teeing anything it writes to stdout into the active
+// transcript. When no transcript is recording it just runs fn directly, so
+// there's no pipe overhead on the common path.
+func (e *replEnv) runLogged(fn func()) {
+	if e.transcript == nil {
+		fn()
+		return
+	}
+	e.transcript.Log("output", withCapturedStdout(fn))
+}
+
+// withCapturedStdout runs fn with os.Stdout temporarily redirected through a
+// pipe, then prints the captured output to the real stdout once fn returns.
+//
+// This is a fragile global-state approach, acceptable here only because the
+// REPL drives everything from a single goroutine and each command's output
+// is small and short-lived: os.Stdout is swapped process-wide for fn's
+// duration, so (a) the output only appears after fn returns, not as it's
+// produced, and (b) any other goroutine that writes to stdout during that
+// window is silently captured into the transcript (and reordered) instead
+// of reaching the terminal. A command context that threaded an io.Writer
+// through to every command would avoid both, at the cost of plumbing it
+// through every $command's Run func; revisit if commands start running
+// concurrently with REPL input or producing large/streaming output.
+func withCapturedStdout(fn func()) string {
+	old := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		fn()
+		return ""
+	}
+	os.Stdout = w
+
+	captured := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, r)
+		captured <- buf.String()
+	}()
+
+	fn()
+
+	_ = w.Close()
+	os.Stdout = old
+	output := <-captured
+
+	io.WriteString(os.Stdout, output)
+	return output
+}